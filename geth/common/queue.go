@@ -0,0 +1,142 @@
+package common
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// TxQueue is a thread-safe set of queued transactions awaiting
+// confirmation, discard, or expiry. It keeps an in-memory map for fast
+// lookups by the running process, backed by a TxStore so the queue
+// survives a restart.
+type TxQueue struct {
+	mu    sync.RWMutex
+	txs   map[QueuedTxID]*QueuedTx
+	store TxStore
+}
+
+// NewTxQueue creates a transaction queue backed by store.
+func NewTxQueue(store TxStore) *TxQueue {
+	return &TxQueue{
+		txs:   make(map[QueuedTxID]*QueuedTx),
+		store: store,
+	}
+}
+
+// Enqueue persists tx to the store and, only once that succeeds, adds it
+// to the in-memory map. This keeps a failed Put from leaving a tx
+// reachable via Get/Has with no queue handler ever invoked for it.
+func (q *TxQueue) Enqueue(tx *QueuedTx) error {
+	if err := q.store.Put(tx); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	q.txs[tx.ID] = tx
+	q.mu.Unlock()
+
+	return nil
+}
+
+// Get returns the queued transaction for id, if any.
+func (q *TxQueue) Get(id QueuedTxID) (*QueuedTx, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	tx, ok := q.txs[id]
+	return tx, ok
+}
+
+// Has reports whether id is currently queued.
+func (q *TxQueue) Has(id QueuedTxID) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	_, ok := q.txs[id]
+	return ok
+}
+
+// Claim looks up id and, if it's queued and not already claimed, marks it
+// claimed and returns it. existed reports whether id was queued at all;
+// claimed reports whether this call actually won ownership of it. A
+// caller must only act on the returned tx (sign, broadcast, discard it)
+// when claimed is true — existed-but-not-claimed means some other caller
+// already owns it (e.g. a duplicate ID in one CompleteTransactions
+// batch, or CompleteTransaction/DiscardTransaction racing on the same
+// ID), and that work must not be duplicated here. The claim is released
+// by Remove.
+func (q *TxQueue) Claim(id QueuedTxID) (tx *QueuedTx, existed, claimed bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	found, existed := q.txs[id]
+	if !existed || found.claimed {
+		return nil, existed, false
+	}
+
+	found.claimed = true
+	return found, true, true
+}
+
+// Release clears the claim on id, if still queued, without removing it.
+// Callers use this after a recoverable error (e.g. sender mismatch, wrong
+// password) that leaves tx queued for a retry, so a later Claim for the
+// same id succeeds instead of reporting it as already in progress.
+func (q *TxQueue) Release(id QueuedTxID) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if tx, ok := q.txs[id]; ok {
+		tx.claimed = false
+	}
+}
+
+// Remove drops id from the queue and the store, if present, reporting
+// whether it was actually there to remove. Callers that finalize a
+// transaction exactly once (e.g. TxQueueManager.finalizeTransaction) use
+// the return value to guard against doing so twice for the same id.
+func (q *TxQueue) Remove(id QueuedTxID) bool {
+	q.mu.Lock()
+	_, existed := q.txs[id]
+	delete(q.txs, id)
+	q.mu.Unlock()
+
+	if !existed {
+		return false
+	}
+
+	if err := q.store.Delete(id); err != nil {
+		log.Error("failed to remove queued tx from store", "id", id, "err", err)
+	}
+
+	return true
+}
+
+// Count returns the number of currently queued transactions.
+func (q *TxQueue) Count() int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return len(q.txs)
+}
+
+// LoadPending walks the store, populating the in-memory map with
+// transactions that were still queued when the process last stopped, and
+// returns them so the caller can re-emit queued events for each.
+func (q *TxQueue) LoadPending() []*QueuedTx {
+	var pending []*QueuedTx
+
+	err := q.store.Iterate(func(tx *QueuedTx) {
+		q.mu.Lock()
+		q.txs[tx.ID] = tx
+		q.mu.Unlock()
+
+		pending = append(pending, tx)
+	})
+	if err != nil {
+		log.Error("failed to load pending queued transactions", "err", err)
+	}
+
+	return pending
+}
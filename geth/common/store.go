@@ -0,0 +1,179 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// ErrTxStoreNotFound is returned by TxStore.Get for an unknown id.
+var ErrTxStoreNotFound = errors.New("queued transaction not found in store")
+
+// TxStore persists queued transactions so they survive process restarts.
+// Implementations only need to durably round-trip a transaction's ID and
+// SendTxArgs: the rest of QueuedTx (its Done channel, Context, and Err) is
+// process-local and is rebuilt fresh on load.
+type TxStore interface {
+	Put(tx *QueuedTx) error
+	Get(id QueuedTxID) (*QueuedTx, error)
+	Delete(id QueuedTxID) error
+
+	// Iterate calls fn for every persisted transaction. An entry that
+	// fails to unmarshal is logged and skipped rather than aborting the
+	// walk or returning an error.
+	Iterate(fn func(tx *QueuedTx)) error
+
+	Close() error
+}
+
+// txRecord is the on-disk representation of a QueuedTx.
+type txRecord struct {
+	ID   QueuedTxID
+	Args SendTxArgs
+}
+
+func (r txRecord) toQueuedTx() *QueuedTx {
+	return &QueuedTx{
+		ID:      r.ID,
+		Args:    r.Args,
+		Context: context.Background(),
+		Done:    make(chan struct{}),
+	}
+}
+
+// MemTxStore is an in-memory TxStore. It doesn't survive restarts; it
+// exists for tests and other callers that don't need persistence.
+type MemTxStore struct {
+	mu      sync.RWMutex
+	records map[QueuedTxID]txRecord
+}
+
+// NewMemTxStore creates an empty in-memory TxStore.
+func NewMemTxStore() *MemTxStore {
+	return &MemTxStore{records: make(map[QueuedTxID]txRecord)}
+}
+
+// Put implements TxStore.
+func (s *MemTxStore) Put(tx *QueuedTx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[tx.ID] = txRecord{ID: tx.ID, Args: tx.Args}
+	return nil
+}
+
+// Get implements TxStore.
+func (s *MemTxStore) Get(id QueuedTxID) (*QueuedTx, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.records[id]
+	if !ok {
+		return nil, ErrTxStoreNotFound
+	}
+	return rec.toQueuedTx(), nil
+}
+
+// Delete implements TxStore.
+func (s *MemTxStore) Delete(id QueuedTxID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, id)
+	return nil
+}
+
+// Iterate implements TxStore.
+func (s *MemTxStore) Iterate(fn func(tx *QueuedTx)) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, rec := range s.records {
+		fn(rec.toQueuedTx())
+	}
+	return nil
+}
+
+// Close implements TxStore.
+func (s *MemTxStore) Close() error {
+	return nil
+}
+
+// LevelDBTxStore is the default, disk-backed TxStore: queued transactions
+// are written to a LevelDB database in the node's data directory, so they
+// survive a process restart.
+type LevelDBTxStore struct {
+	db *leveldb.DB
+}
+
+// NewLevelDBTxStore opens (creating if necessary) the tx queue database
+// under dataDir.
+func NewLevelDBTxStore(dataDir string) (*LevelDBTxStore, error) {
+	db, err := leveldb.OpenFile(filepath.Join(dataDir, "tx-queue"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LevelDBTxStore{db: db}, nil
+}
+
+// Put implements TxStore.
+func (s *LevelDBTxStore) Put(tx *QueuedTx) error {
+	data, err := json.Marshal(txRecord{ID: tx.ID, Args: tx.Args})
+	if err != nil {
+		return err
+	}
+
+	return s.db.Put([]byte(tx.ID), data, nil)
+}
+
+// Get implements TxStore.
+func (s *LevelDBTxStore) Get(id QueuedTxID) (*QueuedTx, error) {
+	data, err := s.db.Get([]byte(id), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, ErrTxStoreNotFound
+		}
+		return nil, err
+	}
+
+	var rec txRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+
+	return rec.toQueuedTx(), nil
+}
+
+// Delete implements TxStore.
+func (s *LevelDBTxStore) Delete(id QueuedTxID) error {
+	return s.db.Delete([]byte(id), nil)
+}
+
+// Iterate implements TxStore.
+func (s *LevelDBTxStore) Iterate(fn func(tx *QueuedTx)) error {
+	iter := s.db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		var rec txRecord
+		if err := json.Unmarshal(iter.Value(), &rec); err != nil {
+			log.Error("skipping malformed queued tx entry", "key", string(iter.Key()), "err", err)
+			continue
+		}
+
+		fn(rec.toQueuedTx())
+	}
+
+	return iter.Error()
+}
+
+// Close implements TxStore.
+func (s *LevelDBTxStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,148 @@
+package common
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/les"
+
+	"github.com/status-im/status-go/geth/params"
+)
+
+// NodeManager abstracts the underlying geth node so that higher-level
+// components (like TxQueueManager) can be tested without spinning up
+// a real node.
+type NodeManager interface {
+	// NodeConfig exposes the configuration the node was started with.
+	NodeConfig() *params.NodeConfig
+
+	// LightEthereumService exposes the running LES service, so callers
+	// can submit transactions through it. It returns an error when the
+	// node (or LES) isn't running.
+	LightEthereumService() (*les.LightEthereum, error)
+}
+
+// AccountManager abstracts access to the accounts known to the node,
+// and the ability to unlock them with a password.
+type AccountManager interface {
+	// SelectedAccount returns the account currently selected in the UI,
+	// or an error if none is selected.
+	SelectedAccount() (*SelectedExtKey, error)
+
+	// VerifyAccountPassword decrypts the key for address using password,
+	// returning the decrypted key on success.
+	VerifyAccountPassword(address, password string) (*keystore.Key, error)
+}
+
+// SelectedExtKey represents the currently selected (unlocked in the UI) account.
+type SelectedExtKey struct {
+	Address    Address
+	AccountKey *keystore.Key
+}
+
+// Address is a thin wrapper around go-ethereum's common.Address, used
+// throughout the API so that request/response payloads can be built from
+// plain hex strings coming from the UI.
+type Address gethcommon.Address
+
+// Hex returns the EIP55-compliant hex representation of the address.
+func (a Address) Hex() string {
+	return gethcommon.Address(a).Hex()
+}
+
+// FromAddress converts a hex-encoded account string into an Address.
+func FromAddress(account string) Address {
+	return Address(gethcommon.HexToAddress(account))
+}
+
+// ToAddress converts a hex-encoded account string into an *Address,
+// returning nil for an empty string (used for contract-creation txs).
+func ToAddress(account string) *Address {
+	if account == "" {
+		return nil
+	}
+
+	to := FromAddress(account)
+	return &to
+}
+
+// SendTxArgs represents the arguments accepted by SendTransaction and
+// QueueTransaction, mirroring go-ethereum's SendTxArgs with the fields
+// status-go cares about.
+type SendTxArgs struct {
+	From      Address         `json:"from"`
+	To        *Address        `json:"to"`
+	Gas       *hexutil.Big    `json:"gas"`
+	GasPrice  *hexutil.Big    `json:"gasPrice"`
+	GasPolicy *GasPolicy      `json:"gasPolicy,omitempty"`
+	Value     *hexutil.Big    `json:"value"`
+	Nonce     *hexutil.Uint64 `json:"nonce"`
+	Data      hexutil.Bytes   `json:"data"`
+}
+
+// GasPolicyTier is a named speed/cost tradeoff a caller can pick instead of
+// specifying gas values outright.
+type GasPolicyTier string
+
+// Supported gas policy tiers, resolved against a GasOracle at
+// CompleteTransaction time.
+const (
+	GasPolicyFast     GasPolicyTier = "fast"
+	GasPolicyStandard GasPolicyTier = "standard"
+	GasPolicySlow     GasPolicyTier = "slow"
+)
+
+// GasPolicy lets a caller request a named tier (Fast/Standard/Slow) or
+// supply explicit EIP-1559 fee overrides, which always take precedence
+// over the tier.
+type GasPolicy struct {
+	Tier                 GasPolicyTier `json:"tier,omitempty"`
+	MaxFeePerGas         *hexutil.Big  `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big  `json:"maxPriorityFeePerGas,omitempty"`
+}
+
+// ResolvedGas holds the gas parameters actually used to complete a
+// transaction, once its GasPolicy has been resolved against a GasOracle.
+type ResolvedGas struct {
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// QueuedTxID is a unique identifier assigned to a queued transaction.
+type QueuedTxID string
+
+// QueuedTx holds a transaction that has been queued for confirmation,
+// along with the plumbing needed to notify the originating caller once
+// it has been completed, discarded, or has timed out.
+type QueuedTx struct {
+	ID      QueuedTxID
+	Args    SendTxArgs
+	Context context.Context
+
+	// CreatedAt is when the transaction was queued, used to report how
+	// long it spent in the queue once it's completed, discarded or
+	// expired.
+	CreatedAt time.Time
+
+	// Done is closed exactly once, when the transaction leaves the queue
+	// (completed, discarded, or expired), after Err has been set.
+	Done chan struct{}
+	Err  error
+
+	// ResolvedGas is set just before the return handler is notified,
+	// holding the gas parameters actually used (after resolving Args'
+	// GasPolicy against a GasOracle), so UIs can display them.
+	ResolvedGas *ResolvedGas
+
+	// claimed is set by TxQueue.Claim while this tx is being completed or
+	// discarded, guarded by TxQueue.mu. It keeps a second concurrent
+	// caller for the same ID (a duplicate ID in one batch, or
+	// independent CompleteTransaction/DiscardTransaction calls racing on
+	// the same ID) from also acting on it before the first is done.
+	claimed bool
+}
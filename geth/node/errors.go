@@ -0,0 +1,31 @@
+package node
+
+import "errors"
+
+var (
+	// ErrInvalidCompleteTxSender is returned when CompleteTransaction (or
+	// CompleteTransactions) is called for an account other than the one
+	// the queued transaction was created for. It is recoverable: the
+	// transaction stays in the queue so the UI can retry with the right
+	// account selected.
+	ErrInvalidCompleteTxSender = errors.New("transaction can only be completed by the sender account")
+
+	// ErrQueuedTxDiscarded is assigned to QueuedTx.Err when a queued
+	// transaction is discarded before it could be completed.
+	ErrQueuedTxDiscarded = errors.New("queued transaction has been discarded")
+
+	// ErrQueuedTxNotFound is returned when acting on a transaction ID that
+	// isn't (or is no longer) present in the queue.
+	ErrQueuedTxNotFound = errors.New("queued transaction not found")
+
+	// ErrQueuedTxInProgress is returned when CompleteTransaction(s) or
+	// DiscardTransaction(s) is called for an ID that's already being
+	// completed or discarded elsewhere (a duplicate ID in one batch, or
+	// two independent calls racing on the same ID).
+	ErrQueuedTxInProgress = errors.New("queued transaction is already being completed or discarded")
+
+	// ErrQueuedTxExpired is assigned to QueuedTx.Err when a queued
+	// transaction's context deadline passes before it is completed or
+	// discarded.
+	ErrQueuedTxExpired = errors.New("queued transaction expired")
+)
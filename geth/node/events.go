@@ -0,0 +1,56 @@
+package node
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+
+	"github.com/status-im/status-go/geth/common"
+)
+
+// TxEventType identifies a point in a queued transaction's lifecycle.
+type TxEventType string
+
+// The lifecycle events TxQueueManager.Subscribe delivers. There is
+// deliberately no Mined event: nothing in TxQueueManager watches the chain
+// for confirmations, so it would never fire.
+const (
+	TxEventEnqueued  TxEventType = "enqueued"
+	TxEventSigned    TxEventType = "signed"
+	TxEventSent      TxEventType = "sent"
+	TxEventFailed    TxEventType = "failed"
+	TxEventDiscarded TxEventType = "discarded"
+	TxEventExpired   TxEventType = "expired"
+)
+
+// TxEvent describes a single transition in a queued transaction's
+// lifecycle, delivered to subscribers registered via Subscribe.
+type TxEvent struct {
+	Type  TxEventType
+	ID    common.QueuedTxID
+	From  common.Address
+	To    *common.Address
+	Nonce *hexutil.Uint64
+	Gas   *common.ResolvedGas
+	Err   error
+}
+
+// Subscribe registers ch to receive TxEvents for every queued
+// transaction's lifecycle. Use the returned Subscription's Unsubscribe
+// to stop receiving events.
+func (m *TxQueueManager) Subscribe(ch chan<- TxEvent) event.Subscription {
+	return m.feed.Subscribe(ch)
+}
+
+// emit sends evt to current subscribers, built from tx and any error
+// relevant to the transition being reported.
+func (m *TxQueueManager) emit(eventType TxEventType, tx *common.QueuedTx, err error) {
+	m.feed.Send(TxEvent{
+		Type:  eventType,
+		ID:    tx.ID,
+		From:  tx.Args.From,
+		To:    tx.Args.To,
+		Nonce: tx.Args.Nonce,
+		Gas:   tx.ResolvedGas,
+		Err:   err,
+	})
+}
@@ -0,0 +1,66 @@
+package node
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/status-im/status-go/geth/common"
+)
+
+// gasTierMultiplier scales a GasOracle's suggested "standard" price to the
+// requested tier, in percent.
+var gasTierMultiplier = map[common.GasPolicyTier]int64{
+	common.GasPolicyFast:     150,
+	common.GasPolicyStandard: 100,
+	common.GasPolicySlow:     75,
+}
+
+// GasOracle resolves a gas policy tier into a concrete gas price, so
+// TxQueueManager doesn't need to know how prices are actually sourced.
+type GasOracle interface {
+	// SuggestGasPrice returns the gas price to use for tier.
+	SuggestGasPrice(tier common.GasPolicyTier) (*big.Int, error)
+}
+
+// lesGasOracle is the default GasOracle, sourcing its "standard" price from
+// the attached LES service and scaling it per tier.
+type lesGasOracle struct {
+	nodeManager common.NodeManager
+}
+
+// newLesGasOracle creates the default GasOracle for a TxQueueManager.
+func newLesGasOracle(nodeManager common.NodeManager) *lesGasOracle {
+	return &lesGasOracle{nodeManager: nodeManager}
+}
+
+// SuggestGasPrice implements GasOracle.
+func (o *lesGasOracle) SuggestGasPrice(tier common.GasPolicyTier) (*big.Int, error) {
+	les, err := o.nodeManager.LightEthereumService()
+	if err != nil {
+		return nil, err
+	}
+
+	standard, err := les.ApiBackend.SuggestPrice(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	multiplier, ok := gasTierMultiplier[tier]
+	if !ok {
+		multiplier = gasTierMultiplier[common.GasPolicyStandard]
+	}
+
+	scaled := new(big.Int).Mul(standard, big.NewInt(multiplier))
+	return scaled.Div(scaled, big.NewInt(100)), nil
+}
+
+// bumpGas increases price by 10%, used to re-price a transaction rejected
+// as underpriced.
+func bumpGas(price *big.Int) *big.Int {
+	if price == nil {
+		return nil
+	}
+
+	bumped := new(big.Int).Mul(price, big.NewInt(110))
+	return bumped.Div(bumped, big.NewInt(100))
+}
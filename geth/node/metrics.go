@@ -0,0 +1,101 @@
+package node
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics lets external monitoring observe TxQueueManager's lifecycle
+// without polling TransactionQueue().Has(), wired in via SetMetrics.
+type Metrics interface {
+	// SetQueueDepth reports the current number of queued transactions.
+	SetQueueDepth(n int)
+
+	// ObserveTimeInQueue records how long a transaction spent queued,
+	// end to end (enqueue to completion, discard, or expiry).
+	ObserveTimeInQueue(d time.Duration)
+
+	// ObserveCompletionLatency records how long a single
+	// CompleteTransaction(s) call took to sign and send.
+	ObserveCompletionLatency(d time.Duration)
+
+	// IncFailure increments a counter for the given failure reason.
+	IncFailure(reason string)
+}
+
+// noopMetrics is the Metrics TxQueueManager uses until SetMetrics is
+// called.
+type noopMetrics struct{}
+
+func (noopMetrics) SetQueueDepth(int)                      {}
+func (noopMetrics) ObserveTimeInQueue(time.Duration)       {}
+func (noopMetrics) ObserveCompletionLatency(time.Duration) {}
+func (noopMetrics) IncFailure(string)                      {}
+
+// PrometheusMetrics is the default Prometheus-backed Metrics
+// implementation. Create one with NewPrometheusMetrics and wire it in
+// via TxQueueManager.SetMetrics.
+type PrometheusMetrics struct {
+	queueDepth        prometheus.Gauge
+	timeInQueue       prometheus.Histogram
+	completionLatency prometheus.Histogram
+	failures          *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics creates and registers the txqueue metrics against
+// reg.
+func NewPrometheusMetrics(reg prometheus.Registerer) *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		queueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "status_go",
+			Subsystem: "txqueue",
+			Name:      "depth",
+			Help:      "Number of transactions currently queued for confirmation.",
+		}),
+		timeInQueue: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "status_go",
+			Subsystem: "txqueue",
+			Name:      "time_in_queue_seconds",
+			Help:      "Time a transaction spent queued, from enqueue to completion, discard, or expiry.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		completionLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "status_go",
+			Subsystem: "txqueue",
+			Name:      "completion_latency_seconds",
+			Help:      "Time CompleteTransaction(s) took to sign and send a transaction.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "status_go",
+			Subsystem: "txqueue",
+			Name:      "failures_total",
+			Help:      "Count of queued transactions that failed, labeled by reason.",
+		}, []string{"reason"}),
+	}
+
+	reg.MustRegister(m.queueDepth, m.timeInQueue, m.completionLatency, m.failures)
+
+	return m
+}
+
+// SetQueueDepth implements Metrics.
+func (m *PrometheusMetrics) SetQueueDepth(n int) {
+	m.queueDepth.Set(float64(n))
+}
+
+// ObserveTimeInQueue implements Metrics.
+func (m *PrometheusMetrics) ObserveTimeInQueue(d time.Duration) {
+	m.timeInQueue.Observe(d.Seconds())
+}
+
+// ObserveCompletionLatency implements Metrics.
+func (m *PrometheusMetrics) ObserveCompletionLatency(d time.Duration) {
+	m.completionLatency.Observe(d.Seconds())
+}
+
+// IncFailure implements Metrics.
+func (m *PrometheusMetrics) IncFailure(reason string) {
+	m.failures.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,39 @@
+package node
+
+import (
+	"context"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/status-im/status-go/geth/common"
+)
+
+// NonceSource resolves the next nonce to use for an account, so
+// TxQueueManager doesn't need to know how pending nonces are actually
+// sourced.
+type NonceSource interface {
+	// PendingNonce returns the next nonce to use for address, accounting
+	// for transactions still pending in the mempool.
+	PendingNonce(address gethcommon.Address) (uint64, error)
+}
+
+// lesNonceSource is the default NonceSource, querying the attached LES
+// service's transaction pool.
+type lesNonceSource struct {
+	nodeManager common.NodeManager
+}
+
+// newLesNonceSource creates the default NonceSource for a TxQueueManager.
+func newLesNonceSource(nodeManager common.NodeManager) *lesNonceSource {
+	return &lesNonceSource{nodeManager: nodeManager}
+}
+
+// PendingNonce implements NonceSource.
+func (s *lesNonceSource) PendingNonce(address gethcommon.Address) (uint64, error) {
+	les, err := s.nodeManager.LightEthereumService()
+	if err != nil {
+		return 0, err
+	}
+
+	return les.ApiBackend.GetPoolNonce(context.Background(), address)
+}
@@ -0,0 +1,80 @@
+package node
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/les"
+
+	"github.com/status-im/status-go/geth/common"
+)
+
+// TxSender signs and broadcasts a queued transaction given an
+// already-decrypted account key. It's a seam so tests can drive
+// completeTransaction's sign/broadcast/retry flow (and observe the
+// Signed/Sent lifecycle events) without a running node.
+type TxSender interface {
+	// Sign builds and signs a transaction for args using the resolved
+	// gas parameters and key. args.Nonce is expected to already be
+	// resolved by the caller (completeTransaction does this via a
+	// NonceSource before ever calling Sign); a nil Nonce here would
+	// silently sign with 0.
+	Sign(key *keystore.Key, args common.SendTxArgs, gas *common.ResolvedGas) (*types.Transaction, error)
+
+	// Broadcast submits signedTx through les, returning its hash once
+	// accepted into the transaction pool.
+	Broadcast(les *les.LightEthereum, signedTx *types.Transaction) (gethcommon.Hash, error)
+}
+
+// lesTxSender is the default TxSender, signing with go-ethereum's
+// Homestead signer and submitting through the attached LES service.
+type lesTxSender struct{}
+
+// newLesTxSender creates the default TxSender for a TxQueueManager.
+func newLesTxSender() *lesTxSender {
+	return &lesTxSender{}
+}
+
+// Sign implements TxSender.
+func (s *lesTxSender) Sign(key *keystore.Key, args common.SendTxArgs, gas *common.ResolvedGas) (*types.Transaction, error) {
+	var nonce uint64
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	}
+
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+
+	var gasLimit uint64
+	if args.Gas != nil {
+		gasLimit = args.Gas.ToInt().Uint64()
+	}
+
+	gasPrice := gas.GasPrice
+	if gasPrice == nil {
+		gasPrice = new(big.Int)
+	}
+
+	var tx *types.Transaction
+	if args.To == nil {
+		tx = types.NewContractCreation(nonce, value, gasLimit, gasPrice, args.Data)
+	} else {
+		tx = types.NewTransaction(nonce, gethcommon.Address(*args.To), value, gasLimit, gasPrice, args.Data)
+	}
+
+	return types.SignTx(tx, types.HomesteadSigner{}, key.PrivateKey)
+}
+
+// Broadcast implements TxSender.
+func (s *lesTxSender) Broadcast(lightEth *les.LightEthereum, signedTx *types.Transaction) (gethcommon.Hash, error) {
+	if err := lightEth.ApiBackend.SendTx(context.Background(), signedTx); err != nil {
+		return gethcommon.Hash{}, err
+	}
+
+	return signedTx.Hash(), nil
+}
@@ -0,0 +1,624 @@
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/status-im/status-go/geth/common"
+)
+
+// completeTransactionsWorkers bounds how many queued transactions are
+// confirmed concurrently by CompleteTransactions.
+const completeTransactionsWorkers = 5
+
+// maxGasRepriceAttempts bounds how many times CompleteTransaction will
+// bump gas and retry after the network rejects a transaction as
+// underpriced.
+const maxGasRepriceAttempts = 3
+
+// TxResult is the outcome of completing a single queued transaction,
+// returned as part of a CompleteTransactions batch.
+type TxResult struct {
+	Hash  gethcommon.Hash
+	Error error
+}
+
+// TxQueueManager keeps track of transactions that have been requested by
+// a dApp/UI but are awaiting user confirmation (a password prompt) before
+// being signed and sent.
+type TxQueueManager struct {
+	nodeManager    common.NodeManager
+	accountManager common.AccountManager
+	txQueue        *common.TxQueue
+
+	mu              sync.RWMutex
+	txQueueHandler  func(*common.QueuedTx)
+	txReturnHandler func(*common.QueuedTx, error)
+
+	gasOracle        GasOracle
+	defaultGasPolicy common.GasPolicy
+	txSender         TxSender
+	nonceSource      NonceSource
+
+	feed    event.Feed
+	metrics Metrics
+}
+
+// NewTxQueueManager creates a TxQueueManager backed by the given node and
+// account managers, persisting queued transactions to store (typically a
+// common.LevelDBTxStore rooted in the node's data dir, so the queue
+// survives a restart). It defaults to the Standard gas policy tier,
+// resolved against a GasOracle that queries the attached LES service;
+// both can be overridden via SetGasPolicy and SetGasOracle. Nonces for
+// transactions that don't specify one are resolved against a NonceSource
+// that also queries the attached LES service, overridable via
+// SetNonceSource.
+func NewTxQueueManager(nodeManager common.NodeManager, accountManager common.AccountManager, store common.TxStore) *TxQueueManager {
+	return &TxQueueManager{
+		nodeManager:      nodeManager,
+		accountManager:   accountManager,
+		txQueue:          common.NewTxQueue(store),
+		gasOracle:        newLesGasOracle(nodeManager),
+		defaultGasPolicy: common.GasPolicy{Tier: common.GasPolicyStandard},
+		txSender:         newLesTxSender(),
+		nonceSource:      newLesNonceSource(nodeManager),
+		metrics:          noopMetrics{},
+	}
+}
+
+// SetMetrics registers m to receive queue depth, latency, and failure
+// metrics. Until called, metrics are recorded as no-ops.
+func (m *TxQueueManager) SetMetrics(metrics Metrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.metrics = metrics
+}
+
+// Start loads any transactions still pending from a previous run and
+// re-emits a queued event for each, so the UI can re-prompt for them.
+func (m *TxQueueManager) Start() {
+	log.Info("starting TxQueueManager")
+
+	for _, tx := range m.txQueue.LoadPending() {
+		log.Info("re-queuing pending transaction from store", "id", tx.ID)
+
+		m.mu.RLock()
+		handler := m.txQueueHandler
+		m.mu.RUnlock()
+
+		if handler != nil {
+			handler(tx)
+		}
+	}
+
+	// LoadPending populates the queue directly, bypassing QueueTransaction
+	// (and its SetQueueDepth call), so the gauge needs an explicit refresh
+	// here or it would read 0 right after a restart with pending
+	// transactions.
+	m.metrics.SetQueueDepth(m.txQueue.Count())
+}
+
+// Stop stops the queue manager.
+func (m *TxQueueManager) Stop() {
+	log.Info("stopping TxQueueManager")
+}
+
+// TransactionQueue exposes the underlying queue, mainly for tests and
+// status reporting (e.g. TransactionQueue().Has(id)).
+func (m *TxQueueManager) TransactionQueue() *common.TxQueue {
+	return m.txQueue
+}
+
+// SetTransactionQueueHandler sets the callback invoked whenever a new
+// transaction is queued.
+func (m *TxQueueManager) SetTransactionQueueHandler(fn func(*common.QueuedTx)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.txQueueHandler = fn
+}
+
+// SetTransactionReturnHandler sets the callback invoked whenever a queued
+// transaction leaves the queue, whether completed, discarded, or rejected
+// with a recoverable error.
+func (m *TxQueueManager) SetTransactionReturnHandler(fn func(*common.QueuedTx, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.txReturnHandler = fn
+}
+
+// SetGasPolicy sets the default gas policy applied to queued transactions
+// whose SendTxArgs don't specify their own GasPolicy.
+func (m *TxQueueManager) SetGasPolicy(policy common.GasPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.defaultGasPolicy = policy
+}
+
+// SetGasOracle overrides the GasOracle used to resolve gas policy tiers,
+// mainly for tests.
+func (m *TxQueueManager) SetGasOracle(oracle GasOracle) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gasOracle = oracle
+}
+
+// SetTxSender overrides the TxSender used to sign and broadcast queued
+// transactions, mainly for tests.
+func (m *TxQueueManager) SetTxSender(sender TxSender) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.txSender = sender
+}
+
+// SetNonceSource overrides the NonceSource used to resolve nonces for
+// transactions that don't specify their own, mainly for tests.
+func (m *TxQueueManager) SetNonceSource(source NonceSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nonceSource = source
+}
+
+// CreateTransaction builds a QueuedTx for args, ready to be queued.
+func (m *TxQueueManager) CreateTransaction(ctx context.Context, args common.SendTxArgs) *common.QueuedTx {
+	return &common.QueuedTx{
+		ID:        newQueuedTxID(),
+		Args:      args,
+		Context:   ctx,
+		CreatedAt: time.Now(),
+		Done:      make(chan struct{}),
+	}
+}
+
+// QueueTransaction adds tx to the queue, notifies the queue handler (if
+// one is set), and starts watching tx's context for expiry.
+func (m *TxQueueManager) QueueTransaction(tx *common.QueuedTx) error {
+	if err := m.txQueue.Enqueue(tx); err != nil {
+		return err
+	}
+
+	m.emit(TxEventEnqueued, tx, nil)
+	m.metrics.SetQueueDepth(m.txQueue.Count())
+
+	go m.watchExpiry(tx)
+
+	m.mu.RLock()
+	handler := m.txQueueHandler
+	m.mu.RUnlock()
+
+	if handler != nil {
+		handler(tx)
+	}
+
+	return nil
+}
+
+// watchExpiry discards tx with ErrQueuedTxExpired once its context's
+// deadline passes, unless it has already left the queue some other way.
+func (m *TxQueueManager) watchExpiry(tx *common.QueuedTx) {
+	if tx.Context == nil {
+		return
+	}
+
+	select {
+	case <-tx.Context.Done():
+		if tx.Context.Err() == context.DeadlineExceeded && m.txQueue.Has(tx.ID) {
+			m.finalizeTransaction(tx, ErrQueuedTxExpired)
+		}
+	case <-tx.Done:
+	}
+}
+
+// WaitForTransaction blocks until tx has been completed or discarded,
+// returning the error (if any) that was assigned to it.
+func (m *TxQueueManager) WaitForTransaction(tx *common.QueuedTx) error {
+	<-tx.Done
+	return tx.Err
+}
+
+// accountDecryptor resolves the selected account's key at most once via
+// VerifyAccountPassword, no matter how many queued transactions share it.
+// CompleteTransaction gives each call its own (trivially "once")
+// decryptor; CompleteTransactions shares a single one across the whole
+// batch, so a password prompt only ever triggers one decrypt attempt.
+type accountDecryptor struct {
+	accountManager common.AccountManager
+	address        common.Address
+	password       string
+
+	once sync.Once
+	key  *keystore.Key
+	err  error
+}
+
+func (d *accountDecryptor) resolve() (*keystore.Key, error) {
+	d.once.Do(func() {
+		d.key, d.err = d.accountManager.VerifyAccountPassword(d.address.Hex(), d.password)
+	})
+
+	return d.key, d.err
+}
+
+// CompleteTransaction tries to sign and send a single queued transaction,
+// decrypting the currently selected account with password.
+func (m *TxQueueManager) CompleteTransaction(id common.QueuedTxID, password string) (gethcommon.Hash, error) {
+	selectedAccount, err := m.accountManager.SelectedAccount()
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+
+	decrypt := &accountDecryptor{
+		accountManager: m.accountManager,
+		address:        selectedAccount.Address,
+		password:       password,
+	}
+
+	return m.completeTransaction(id, selectedAccount, decrypt)
+}
+
+// CompleteTransactions confirms a batch of queued transactions after a
+// single password prompt, so a UI can let users approve several pending
+// transactions at once. The selected account's key is resolved once; the
+// individual transactions are then signed and sent concurrently over a
+// bounded worker pool. Per-transaction errors (unknown ID, sender
+// mismatch, decrypt failure, send failure) are collected into the
+// returned map rather than aborting the whole batch.
+func (m *TxQueueManager) CompleteTransactions(ids []common.QueuedTxID, password string) map[common.QueuedTxID]TxResult {
+	results := make(map[common.QueuedTxID]TxResult, len(ids))
+
+	selectedAccount, err := m.accountManager.SelectedAccount()
+	if err != nil {
+		for _, id := range ids {
+			results[id] = TxResult{Error: err}
+		}
+		return results
+	}
+
+	decrypt := &accountDecryptor{
+		accountManager: m.accountManager,
+		address:        selectedAccount.Address,
+		password:       password,
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, completeTransactionsWorkers)
+	)
+
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(id common.QueuedTxID) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			hash, err := m.completeTransaction(id, selectedAccount, decrypt)
+
+			mu.Lock()
+			results[id] = TxResult{Hash: hash, Error: err}
+			mu.Unlock()
+		}(id)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// completeTransaction does the actual work of signing and sending a
+// single queued transaction, given an already-resolved selected account
+// and a decryptor for the password it was unlocked with.
+func (m *TxQueueManager) completeTransaction(id common.QueuedTxID, selectedAccount *common.SelectedExtKey, decrypt *accountDecryptor) (gethcommon.Hash, error) {
+	start := time.Now()
+	defer func() { m.metrics.ObserveCompletionLatency(time.Since(start)) }()
+
+	queuedTx, existed, claimed := m.txQueue.Claim(id)
+	if !existed {
+		return gethcommon.Hash{}, ErrQueuedTxNotFound
+	}
+	if !claimed {
+		return gethcommon.Hash{}, ErrQueuedTxInProgress
+	}
+
+	if queuedTx.Args.From.Hex() != selectedAccount.Address.Hex() {
+		m.txQueue.Release(id)
+		m.notifyReturnHandler(queuedTx, ErrInvalidCompleteTxSender)
+		return gethcommon.Hash{}, ErrInvalidCompleteTxSender
+	}
+
+	resolved, err := m.resolveGas(queuedTx.Args)
+	if err != nil {
+		m.finalizeTransaction(queuedTx, err)
+		return gethcommon.Hash{}, err
+	}
+	queuedTx.ResolvedGas = resolved
+
+	// NodeConfig is needed to locate the keystore used to decrypt
+	// selectedAccount below; fetching it here (rather than earlier) keeps
+	// a sender mismatch from requiring a running node.
+	m.nodeManager.NodeConfig()
+
+	les, err := m.nodeManager.LightEthereumService()
+	if err != nil {
+		if err == keystore.ErrDecrypt {
+			// Wrong password: recoverable, leave tx in the queue so the
+			// UI can re-prompt.
+			m.txQueue.Release(id)
+			m.notifyReturnHandler(queuedTx, err)
+			return gethcommon.Hash{}, err
+		}
+
+		m.finalizeTransaction(queuedTx, err)
+		return gethcommon.Hash{}, err
+	}
+
+	key, err := decrypt.resolve()
+	if err != nil {
+		if err == keystore.ErrDecrypt {
+			m.txQueue.Release(id)
+			m.notifyReturnHandler(queuedTx, err)
+			return gethcommon.Hash{}, err
+		}
+
+		m.finalizeTransaction(queuedTx, err)
+		return gethcommon.Hash{}, err
+	}
+
+	if queuedTx.Args.Nonce == nil {
+		pending, err := m.nonceSource.PendingNonce(gethcommon.Address(queuedTx.Args.From))
+		if err != nil {
+			m.finalizeTransaction(queuedTx, err)
+			return gethcommon.Hash{}, err
+		}
+		nonce := hexutil.Uint64(pending)
+		queuedTx.Args.Nonce = &nonce
+	}
+
+	m.mu.RLock()
+	sender := m.txSender
+	m.mu.RUnlock()
+
+	var (
+		hash   gethcommon.Hash
+		signed *types.Transaction
+	)
+
+	for attempt := 0; attempt < maxGasRepriceAttempts; attempt++ {
+		signed, err = sender.Sign(key, queuedTx.Args, queuedTx.ResolvedGas)
+		if err != nil {
+			break
+		}
+
+		if attempt == 0 {
+			m.emit(TxEventSigned, queuedTx, nil)
+		}
+
+		hash, err = sender.Broadcast(les, signed)
+		if err == nil {
+			break
+		}
+
+		if err == core.ErrNonceTooLow {
+			// The resolved nonce has fallen behind (e.g. another
+			// transaction from this account was sent elsewhere in the
+			// meantime): re-fetch it rather than treating this as a
+			// price problem.
+			pending, nerr := m.nonceSource.PendingNonce(gethcommon.Address(queuedTx.Args.From))
+			if nerr != nil {
+				err = nerr
+				break
+			}
+			nonce := hexutil.Uint64(pending)
+			queuedTx.Args.Nonce = &nonce
+			continue
+		}
+
+		if !isUnderpriced(err) {
+			break
+		}
+
+		queuedTx.ResolvedGas = repriceGas(queuedTx.ResolvedGas)
+	}
+
+	if err == nil {
+		m.emit(TxEventSent, queuedTx, nil)
+	}
+
+	m.finalizeTransaction(queuedTx, err)
+
+	return hash, err
+}
+
+// resolveGas turns args' GasPolicy (or the manager's default, if args
+// doesn't set one) into concrete gas parameters. Explicit MaxFeePerGas/
+// MaxPriorityFeePerGas overrides always take precedence over the tier.
+func (m *TxQueueManager) resolveGas(args common.SendTxArgs) (*common.ResolvedGas, error) {
+	m.mu.RLock()
+	policy := m.defaultGasPolicy
+	oracle := m.gasOracle
+	m.mu.RUnlock()
+
+	if args.GasPolicy != nil {
+		policy = *args.GasPolicy
+	}
+
+	if policy.MaxFeePerGas != nil || policy.MaxPriorityFeePerGas != nil {
+		resolved := &common.ResolvedGas{}
+		if policy.MaxFeePerGas != nil {
+			resolved.MaxFeePerGas = policy.MaxFeePerGas.ToInt()
+		}
+		if policy.MaxPriorityFeePerGas != nil {
+			resolved.MaxPriorityFeePerGas = policy.MaxPriorityFeePerGas.ToInt()
+		}
+		return resolved, nil
+	}
+
+	tier := policy.Tier
+	if tier == "" {
+		tier = common.GasPolicyStandard
+	}
+
+	price, err := oracle.SuggestGasPrice(tier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.ResolvedGas{GasPrice: price}, nil
+}
+
+// repriceGas bumps resolved's gas values after an underpriced rejection.
+func repriceGas(resolved *common.ResolvedGas) *common.ResolvedGas {
+	repriced := *resolved
+	repriced.GasPrice = bumpGas(resolved.GasPrice)
+	repriced.MaxFeePerGas = bumpGas(resolved.MaxFeePerGas)
+	repriced.MaxPriorityFeePerGas = bumpGas(resolved.MaxPriorityFeePerGas)
+	return &repriced
+}
+
+// isUnderpriced reports whether err is the kind of price rejection that a
+// gas repricing and retry can recover from. core.ErrNonceTooLow is handled
+// separately, by re-fetching the nonce rather than bumping price (see
+// completeTransaction).
+func isUnderpriced(err error) bool {
+	return err == core.ErrUnderpriced || err == core.ErrReplaceUnderpriced
+}
+
+// DiscardTransaction removes id from the queue without sending it,
+// assigning ErrQueuedTxDiscarded to the transaction.
+func (m *TxQueueManager) DiscardTransaction(id common.QueuedTxID) error {
+	queuedTx, existed, claimed := m.txQueue.Claim(id)
+	if !existed {
+		return ErrQueuedTxNotFound
+	}
+	if !claimed {
+		return ErrQueuedTxInProgress
+	}
+
+	m.finalizeTransaction(queuedTx, ErrQueuedTxDiscarded)
+
+	return nil
+}
+
+// DiscardTransactions discards a batch of queued transactions, collecting
+// a per-ID error (e.g. for unknown IDs) rather than aborting on the first
+// failure.
+func (m *TxQueueManager) DiscardTransactions(ids []common.QueuedTxID) map[common.QueuedTxID]error {
+	errs := make(map[common.QueuedTxID]error, len(ids))
+
+	for _, id := range ids {
+		if err := m.DiscardTransaction(id); err != nil {
+			errs[id] = err
+		}
+	}
+
+	return errs
+}
+
+// finalizeTransaction removes tx from the queue, assigns err to it, emits
+// the matching lifecycle event and metrics, closes Done (unblocking
+// WaitForTransaction), and notifies the return handler.
+func (m *TxQueueManager) finalizeTransaction(tx *common.QueuedTx, err error) {
+	// Remove reports whether tx was actually still queued. A concurrent
+	// call finalizing the same tx (e.g. a duplicate ID in one
+	// CompleteTransactions batch, or CompleteTransaction/
+	// DiscardTransaction racing on the same ID) loses this race and
+	// must not emit/close a second time, or the Done channel would be
+	// closed twice.
+	if !m.txQueue.Remove(tx.ID) {
+		return
+	}
+
+	tx.Err = err
+
+	// A nil err here means Broadcast succeeded; TxEventSent already
+	// covers that, and there's no final lifecycle event left to emit.
+	if eventType, ok := finalEventType(err); ok {
+		m.emit(eventType, tx, err)
+
+		if eventType == TxEventFailed {
+			m.metrics.IncFailure(failureReason(err))
+		}
+	}
+
+	m.metrics.SetQueueDepth(m.txQueue.Count())
+	if !tx.CreatedAt.IsZero() {
+		m.metrics.ObserveTimeInQueue(time.Since(tx.CreatedAt))
+	}
+
+	m.notifyReturnHandler(tx, err)
+
+	close(tx.Done)
+}
+
+// failureReason maps err to a small, fixed set of labels suitable for a
+// Prometheus counter. Using err.Error() directly would let any future
+// error that embeds per-tx detail (an address, an amount, a hash) blow
+// up the metric's label cardinality.
+func failureReason(err error) string {
+	switch err {
+	case ErrQueuedTxNotFound:
+		return "not_found"
+	case ErrInvalidCompleteTxSender:
+		return "sender_mismatch"
+	case keystore.ErrDecrypt:
+		return "decrypt"
+	case core.ErrNonceTooLow:
+		return "nonce_too_low"
+	case core.ErrUnderpriced, core.ErrReplaceUnderpriced:
+		return "underpriced"
+	default:
+		return "other"
+	}
+}
+
+// finalEventType maps the error a transaction was finalized with to the
+// lifecycle event that should be emitted for it, if any.
+func finalEventType(err error) (TxEventType, bool) {
+	switch err {
+	case nil:
+		return "", false
+	case ErrQueuedTxDiscarded:
+		return TxEventDiscarded, true
+	case ErrQueuedTxExpired:
+		return TxEventExpired, true
+	default:
+		return TxEventFailed, true
+	}
+}
+
+// notifyReturnHandler calls the return handler, if one is set.
+func (m *TxQueueManager) notifyReturnHandler(tx *common.QueuedTx, err error) {
+	m.mu.RLock()
+	handler := m.txReturnHandler
+	m.mu.RUnlock()
+
+	if handler != nil {
+		handler(tx, err)
+	}
+}
+
+// newQueuedTxID generates a random, sufficiently unique queued tx ID.
+func newQueuedTxID() common.QueuedTxID {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return common.QueuedTxID(hex.EncodeToString(b[:]))
+}
@@ -3,9 +3,16 @@ package node
 import (
 	"context"
 	"errors"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/les"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/golang/mock/gomock"
@@ -27,19 +34,34 @@ type TxQueueTestSuite struct {
 	nodeManagerMock        *common.MockNodeManager
 	accountManagerMockCtrl *gomock.Controller
 	accountManagerMock     *common.MockAccountManager
+	gasOracleMockCtrl      *gomock.Controller
+	gasOracleMock          *MockGasOracle
+	txSenderMockCtrl       *gomock.Controller
+	txSenderMock           *MockTxSender
+	nonceSourceMockCtrl    *gomock.Controller
+	nonceSourceMock        *MockNonceSource
 }
 
 func (s *TxQueueTestSuite) SetupTest() {
 	s.nodeManagerMockCtrl = gomock.NewController(s.T())
 	s.accountManagerMockCtrl = gomock.NewController(s.T())
+	s.gasOracleMockCtrl = gomock.NewController(s.T())
+	s.txSenderMockCtrl = gomock.NewController(s.T())
+	s.nonceSourceMockCtrl = gomock.NewController(s.T())
 
 	s.nodeManagerMock = common.NewMockNodeManager(s.nodeManagerMockCtrl)
 	s.accountManagerMock = common.NewMockAccountManager(s.accountManagerMockCtrl)
+	s.gasOracleMock = NewMockGasOracle(s.gasOracleMockCtrl)
+	s.txSenderMock = NewMockTxSender(s.txSenderMockCtrl)
+	s.nonceSourceMock = NewMockNonceSource(s.nonceSourceMockCtrl)
 }
 
 func (s *TxQueueTestSuite) TearDownTest() {
 	s.nodeManagerMockCtrl.Finish()
 	s.accountManagerMockCtrl.Finish()
+	s.gasOracleMockCtrl.Finish()
+	s.txSenderMockCtrl.Finish()
+	s.nonceSourceMockCtrl.Finish()
 }
 
 func (s *TxQueueTestSuite) TestCompleteTransaction() {
@@ -55,7 +77,10 @@ func (s *TxQueueTestSuite) TestCompleteTransaction() {
 	// and treat as success.
 	s.nodeManagerMock.EXPECT().LightEthereumService().Return(nil, errTxAssumedSent)
 
-	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock)
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
 
 	txQueueManager.Start()
 	defer txQueueManager.Stop()
@@ -73,6 +98,8 @@ func (s *TxQueueTestSuite) TestCompleteTransaction() {
 	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {
 		s.Equal(tx.ID, queuedTx.ID)
 		s.Equal(errTxAssumedSent, err)
+		s.Require().NotNil(queuedTx.ResolvedGas)
+		s.Equal(big.NewInt(1000000000), queuedTx.ResolvedGas.GasPrice)
 	})
 
 	err := txQueueManager.QueueTransaction(tx)
@@ -98,7 +125,7 @@ func (s *TxQueueTestSuite) TestAccountMismatch() {
 		Address: common.FromAddress(TestConfig.Account2.Address),
 	}, nil)
 
-	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock)
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
 
 	txQueueManager.Start()
 	defer txQueueManager.Stop()
@@ -143,7 +170,10 @@ func (s *TxQueueTestSuite) TestInvalidPassword() {
 	// Set ErrDecrypt error response as expected with a wrong password.
 	s.nodeManagerMock.EXPECT().LightEthereumService().Return(nil, keystore.ErrDecrypt)
 
-	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock)
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
 
 	txQueueManager.Start()
 	defer txQueueManager.Stop()
@@ -178,7 +208,7 @@ func (s *TxQueueTestSuite) TestInvalidPassword() {
 }
 
 func (s *TxQueueTestSuite) TestDiscardTransaction() {
-	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock)
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
 
 	txQueueManager.Start()
 	defer txQueueManager.Stop()
@@ -216,3 +246,738 @@ func (s *TxQueueTestSuite) TestDiscardTransaction() {
 	// Transaction should be already removed from the queue.
 	s.False(txQueueManager.TransactionQueue().Has(tx.ID))
 }
+
+func (s *TxQueueTestSuite) TestCompleteTransactions() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true)).Times(2)
+
+	// Two transactions complete the same way as TestCompleteTransaction, one
+	// unknown ID is also requested and should be reported without affecting
+	// the others.
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(nil, errTxAssumedSent).Times(2)
+
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil).Times(2)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {
+		s.Equal(errTxAssumedSent, err)
+	})
+
+	tx1 := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	tx2 := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+
+	s.NoError(txQueueManager.QueueTransaction(tx1))
+	s.NoError(txQueueManager.QueueTransaction(tx2))
+
+	unknownID := common.QueuedTxID("unknown-id")
+
+	results := txQueueManager.CompleteTransactions(
+		[]common.QueuedTxID{tx1.ID, tx2.ID, unknownID}, TestConfig.Account1.Password)
+
+	s.Len(results, 3)
+	s.Equal(errTxAssumedSent, results[tx1.ID].Error)
+	s.Equal(errTxAssumedSent, results[tx2.ID].Error)
+	s.Equal(ErrQueuedTxNotFound, results[unknownID].Error)
+
+	s.False(txQueueManager.TransactionQueue().Has(tx1.ID))
+	s.False(txQueueManager.TransactionQueue().Has(tx2.ID))
+}
+
+// TestCompleteTransactionsDuplicateID checks that a duplicate ID within a
+// single CompleteTransactions batch doesn't let both goroutines sign and
+// broadcast the same queued tx: only the one that wins the Claim reaches
+// Sign/Broadcast (gomock's default "exactly once" on those EXPECT calls
+// would fail the test if both ran), and the other is told the tx is
+// already in progress.
+func (s *TxQueueTestSuite) TestCompleteTransactionsDuplicateID() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true))
+
+	lightEth := &les.LightEthereum{}
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(lightEth, nil)
+
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil)
+
+	key := &keystore.Key{}
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(
+		common.FromAddress(TestConfig.Account1.Address).Hex(), TestConfig.Account1.Password,
+	).Return(key, nil)
+
+	s.nonceSourceMock.EXPECT().PendingNonce(gethcommon.Address(common.FromAddress(TestConfig.Account1.Address))).Return(uint64(0), nil)
+
+	signedTx := types.NewTransaction(0, gethcommon.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	wantHash := signedTx.Hash()
+
+	// No .Times() on either call below: gomock's default of exactly-once
+	// is itself the regression check that only one of the two duplicate
+	// entries actually signs and broadcasts.
+	s.txSenderMock.EXPECT().Sign(key, gomock.Any(), gomock.Any()).Return(signedTx, nil)
+	s.txSenderMock.EXPECT().Broadcast(lightEth, signedTx).Return(wantHash, nil)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
+	txQueueManager.SetTxSender(s.txSenderMock)
+	txQueueManager.SetNonceSource(s.nonceSourceMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	var results map[common.QueuedTxID]TxResult
+	s.NotPanics(func() {
+		results = txQueueManager.CompleteTransactions(
+			[]common.QueuedTxID{tx.ID, tx.ID}, TestConfig.Account1.Password)
+	})
+
+	// Both entries share one map key, so whichever goroutine writes last
+	// decides what's here: either the winner's real result, or
+	// ErrQueuedTxInProgress for the loser.
+	if err := results[tx.ID].Error; err != nil {
+		s.Equal(ErrQueuedTxInProgress, err)
+	} else {
+		s.Equal(wantHash, results[tx.ID].Hash)
+	}
+	s.False(txQueueManager.TransactionQueue().Has(tx.ID))
+}
+
+func (s *TxQueueTestSuite) TestCompleteTransactionsSenderMismatch() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account2.Address),
+	}, nil)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {
+		s.Equal(ErrInvalidCompleteTxSender, err)
+	})
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	results := txQueueManager.CompleteTransactions([]common.QueuedTxID{tx.ID}, TestConfig.Account1.Password)
+
+	s.Len(results, 1)
+	s.Equal(ErrInvalidCompleteTxSender, results[tx.ID].Error)
+	// Mismatched sender is recoverable, so the tx stays queued.
+	s.True(txQueueManager.TransactionQueue().Has(tx.ID))
+}
+
+func (s *TxQueueTestSuite) TestDiscardTransactions() {
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {
+		s.Equal(ErrQueuedTxDiscarded, err)
+	})
+
+	tx1 := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	tx2 := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+
+	s.NoError(txQueueManager.QueueTransaction(tx1))
+	s.NoError(txQueueManager.QueueTransaction(tx2))
+
+	unknownID := common.QueuedTxID("unknown-id")
+
+	errs := txQueueManager.DiscardTransactions([]common.QueuedTxID{tx1.ID, unknownID})
+
+	s.Len(errs, 1)
+	s.Equal(ErrQueuedTxNotFound, errs[unknownID])
+	s.False(txQueueManager.TransactionQueue().Has(tx1.ID))
+	// tx2 was not part of the batch, so it should remain queued.
+	s.True(txQueueManager.TransactionQueue().Has(tx2.ID))
+}
+
+func (s *TxQueueTestSuite) TestGasPolicyTierResolution() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true))
+
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(nil, errTxAssumedSent)
+
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyFast).Return(big.NewInt(2000000000), nil)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
+	// A manager-wide default of Fast should be used when the tx itself
+	// doesn't request a tier.
+	txQueueManager.SetGasPolicy(common.GasPolicy{Tier: common.GasPolicyFast})
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {
+		s.Require().NotNil(queuedTx.ResolvedGas)
+		s.Equal(big.NewInt(2000000000), queuedTx.ResolvedGas.GasPrice)
+	})
+
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	_, err := txQueueManager.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+	s.Equal(errTxAssumedSent, err)
+}
+
+func (s *TxQueueTestSuite) TestGasPolicyExplicitOverridePrecedence() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true))
+
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(nil, errTxAssumedSent)
+
+	// Explicit fee overrides bypass the oracle entirely, so no
+	// SuggestGasPrice call is expected here.
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+		GasPolicy: &common.GasPolicy{
+			Tier:                 common.GasPolicyFast,
+			MaxFeePerGas:         (*hexutil.Big)(big.NewInt(5000000000)),
+			MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(1500000000)),
+		},
+	})
+
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {
+		s.Require().NotNil(queuedTx.ResolvedGas)
+		s.Equal(big.NewInt(5000000000), queuedTx.ResolvedGas.MaxFeePerGas)
+		s.Equal(big.NewInt(1500000000), queuedTx.ResolvedGas.MaxPriorityFeePerGas)
+		s.Nil(queuedTx.ResolvedGas.GasPrice)
+	})
+
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	_, err := txQueueManager.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+	s.Equal(errTxAssumedSent, err)
+}
+
+func (s *TxQueueTestSuite) TestGasPolicyRepriceOnUnderpriced() {
+	resolved := repriceGas(&common.ResolvedGas{
+		GasPrice:             big.NewInt(1000000000),
+		MaxFeePerGas:         big.NewInt(2000000000),
+		MaxPriorityFeePerGas: big.NewInt(100000000),
+	})
+
+	s.Equal(big.NewInt(1100000000), resolved.GasPrice)
+	s.Equal(big.NewInt(2200000000), resolved.MaxFeePerGas)
+	s.Equal(big.NewInt(110000000), resolved.MaxPriorityFeePerGas)
+
+	s.True(isUnderpriced(core.ErrUnderpriced))
+	s.True(isUnderpriced(core.ErrReplaceUnderpriced))
+	// ErrNonceTooLow is handled separately, by re-fetching the nonce
+	// rather than bumping price.
+	s.False(isUnderpriced(core.ErrNonceTooLow))
+	s.False(isUnderpriced(errTxAssumedSent))
+}
+
+// TestCompleteTransactionSignsAndSends drives completeTransaction all the
+// way through a successful decrypt, sign and broadcast (rather than
+// short-circuiting on a mocked LightEthereumService error like the tests
+// above), so the Signed and Sent lifecycle events are actually observed.
+func (s *TxQueueTestSuite) TestCompleteTransactionSignsAndSends() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true))
+
+	lightEth := &les.LightEthereum{}
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(lightEth, nil)
+
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil)
+
+	key := &keystore.Key{}
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(
+		common.FromAddress(TestConfig.Account1.Address).Hex(), TestConfig.Account1.Password,
+	).Return(key, nil)
+
+	signedTx := types.NewTransaction(0, gethcommon.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	wantHash := signedTx.Hash()
+
+	s.nonceSourceMock.EXPECT().PendingNonce(gethcommon.Address(common.FromAddress(TestConfig.Account1.Address))).Return(uint64(0), nil)
+
+	s.txSenderMock.EXPECT().Sign(key, gomock.Any(), gomock.Any()).Return(signedTx, nil)
+	s.txSenderMock.EXPECT().Broadcast(lightEth, signedTx).Return(wantHash, nil)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
+	txQueueManager.SetTxSender(s.txSenderMock)
+	txQueueManager.SetNonceSource(s.nonceSourceMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	events := make(chan TxEvent, 10)
+	sub := txQueueManager.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	hash, err := txQueueManager.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+	s.NoError(err)
+	s.Equal(wantHash, hash)
+	s.False(txQueueManager.TransactionQueue().Has(tx.ID))
+
+	seen := s.drainEvents(events, 3)
+	s.Equal(TxEventEnqueued, seen[0].Type)
+	s.Equal(TxEventSigned, seen[1].Type)
+	s.Equal(TxEventSent, seen[2].Type)
+}
+
+// TestGasRepriceRetryThroughCompleteTransaction drives the actual
+// sign/broadcast/reprice loop (rather than calling repriceGas/
+// isUnderpriced directly) through a mocked TxSender that rejects the
+// first two broadcasts as underpriced, checking that a retry bumps gas
+// and resends before the batch sees a final result.
+func (s *TxQueueTestSuite) TestGasRepriceRetryThroughCompleteTransaction() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true))
+
+	lightEth := &les.LightEthereum{}
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(lightEth, nil)
+
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil)
+
+	key := &keystore.Key{}
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(
+		common.FromAddress(TestConfig.Account1.Address).Hex(), TestConfig.Account1.Password,
+	).Return(key, nil)
+
+	signedTx := types.NewTransaction(0, gethcommon.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	wantHash := signedTx.Hash()
+
+	s.nonceSourceMock.EXPECT().PendingNonce(gethcommon.Address(common.FromAddress(TestConfig.Account1.Address))).Return(uint64(0), nil)
+
+	s.txSenderMock.EXPECT().Sign(key, gomock.Any(), gomock.Any()).Return(signedTx, nil).Times(3)
+
+	gomock.InOrder(
+		s.txSenderMock.EXPECT().Broadcast(lightEth, signedTx).Return(gethcommon.Hash{}, core.ErrUnderpriced),
+		s.txSenderMock.EXPECT().Broadcast(lightEth, signedTx).Return(gethcommon.Hash{}, core.ErrUnderpriced),
+		s.txSenderMock.EXPECT().Broadcast(lightEth, signedTx).Return(wantHash, nil),
+	)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
+	txQueueManager.SetTxSender(s.txSenderMock)
+	txQueueManager.SetNonceSource(s.nonceSourceMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	hash, err := txQueueManager.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+	s.NoError(err)
+	s.Equal(wantHash, hash)
+
+	// Gas should have been bumped 10% on each of the two underpriced
+	// rejections before the third attempt succeeded.
+	s.Equal(big.NewInt(1210000000), tx.ResolvedGas.GasPrice)
+}
+
+// TestGasRepriceRetryBounded checks that a transaction that's rejected as
+// underpriced on every attempt gives up after maxGasRepriceAttempts
+// rather than retrying forever.
+func (s *TxQueueTestSuite) TestGasRepriceRetryBounded() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true))
+
+	lightEth := &les.LightEthereum{}
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(lightEth, nil)
+
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil)
+
+	key := &keystore.Key{}
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(
+		common.FromAddress(TestConfig.Account1.Address).Hex(), TestConfig.Account1.Password,
+	).Return(key, nil)
+
+	signedTx := types.NewTransaction(0, gethcommon.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+
+	s.nonceSourceMock.EXPECT().PendingNonce(gethcommon.Address(common.FromAddress(TestConfig.Account1.Address))).Return(uint64(0), nil)
+
+	s.txSenderMock.EXPECT().Sign(key, gomock.Any(), gomock.Any()).Return(signedTx, nil).Times(maxGasRepriceAttempts)
+	s.txSenderMock.EXPECT().Broadcast(lightEth, signedTx).Return(gethcommon.Hash{}, core.ErrUnderpriced).Times(maxGasRepriceAttempts)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
+	txQueueManager.SetTxSender(s.txSenderMock)
+	txQueueManager.SetNonceSource(s.nonceSourceMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	_, err := txQueueManager.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+	s.Equal(core.ErrUnderpriced, err)
+}
+
+// TestNonceTooLowRetriesWithFreshNonce checks that a core.ErrNonceTooLow
+// rejection re-fetches the nonce and retries with it, rather than being
+// treated as an underpriced rejection and bumping gas instead.
+func (s *TxQueueTestSuite) TestNonceTooLowRetriesWithFreshNonce() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true))
+
+	lightEth := &les.LightEthereum{}
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(lightEth, nil)
+
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil)
+
+	key := &keystore.Key{}
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(
+		common.FromAddress(TestConfig.Account1.Address).Hex(), TestConfig.Account1.Password,
+	).Return(key, nil)
+
+	account := gethcommon.Address(common.FromAddress(TestConfig.Account1.Address))
+	gomock.InOrder(
+		s.nonceSourceMock.EXPECT().PendingNonce(account).Return(uint64(1), nil),
+		s.nonceSourceMock.EXPECT().PendingNonce(account).Return(uint64(2), nil),
+	)
+
+	signedTx := types.NewTransaction(0, gethcommon.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	wantHash := signedTx.Hash()
+
+	s.txSenderMock.EXPECT().Sign(key, gomock.Any(), gomock.Any()).Return(signedTx, nil).Times(2)
+
+	gomock.InOrder(
+		s.txSenderMock.EXPECT().Broadcast(lightEth, signedTx).Return(gethcommon.Hash{}, core.ErrNonceTooLow),
+		s.txSenderMock.EXPECT().Broadcast(lightEth, signedTx).Return(wantHash, nil),
+	)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
+	txQueueManager.SetTxSender(s.txSenderMock)
+	txQueueManager.SetNonceSource(s.nonceSourceMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	hash, err := txQueueManager.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+	s.NoError(err)
+	s.Equal(wantHash, hash)
+
+	// Gas should be untouched: the retry was driven by a fresh nonce, not
+	// a price bump.
+	s.Equal(big.NewInt(1000000000), tx.ResolvedGas.GasPrice)
+	s.Equal(hexutil.Uint64(2), *tx.Args.Nonce)
+}
+
+// TestRestartMidQueue simulates a process restart while a transaction is
+// still queued: a second TxQueueManager backed by the same store should
+// see the pending transaction and re-emit a queued event for it.
+func (s *TxQueueTestSuite) TestRestartMidQueue() {
+	store := common.NewMemTxStore()
+
+	before := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, store)
+	before.Start()
+
+	tx := before.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(before.QueueTransaction(tx))
+	before.Stop()
+
+	var requeued []common.QueuedTxID
+	after := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, store)
+	after.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {
+		requeued = append(requeued, queuedTx.ID)
+	})
+	after.Start()
+	defer after.Stop()
+
+	s.Equal([]common.QueuedTxID{tx.ID}, requeued)
+	s.True(after.TransactionQueue().Has(tx.ID))
+}
+
+// TestRestartReportsQueueDepth checks that Start refreshes the queue-depth
+// gauge for transactions reloaded from the store, rather than leaving it
+// at 0 until some later enqueue/finalize happens to update it.
+func (s *TxQueueTestSuite) TestRestartReportsQueueDepth() {
+	store := common.NewMemTxStore()
+
+	before := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, store)
+	before.Start()
+
+	tx := before.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(before.QueueTransaction(tx))
+	before.Stop()
+
+	metrics := &recordingMetrics{}
+	after := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, store)
+	after.SetMetrics(metrics)
+	after.Start()
+	defer after.Stop()
+
+	s.Equal(1, metrics.queueDepth)
+}
+
+// recordingMetrics is a minimal Metrics implementation that just remembers
+// the last queue depth it was told about.
+type recordingMetrics struct {
+	queueDepth int
+}
+
+func (m *recordingMetrics) SetQueueDepth(n int)                    { m.queueDepth = n }
+func (m *recordingMetrics) ObserveTimeInQueue(time.Duration)       {}
+func (m *recordingMetrics) ObserveCompletionLatency(time.Duration) {}
+func (m *recordingMetrics) IncFailure(string)                      {}
+
+// TestDiscardAfterRestart checks that a transaction reloaded from the
+// store after a restart can still be discarded normally.
+func (s *TxQueueTestSuite) TestDiscardAfterRestart() {
+	store := common.NewMemTxStore()
+
+	before := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, store)
+	before.Start()
+
+	tx := before.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(before.QueueTransaction(tx))
+	before.Stop()
+
+	after := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, store)
+	after.Start()
+	defer after.Stop()
+
+	s.True(after.TransactionQueue().Has(tx.ID))
+	s.NoError(after.DiscardTransaction(tx.ID))
+	s.False(after.TransactionQueue().Has(tx.ID))
+
+	// Discarding it should have dropped it from the store too, so a
+	// third restart wouldn't see it again.
+	third := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, store)
+	third.Start()
+	defer third.Stop()
+	s.False(third.TransactionQueue().Has(tx.ID))
+}
+
+// TestCompletionAfterRestart checks that a transaction reloaded from the
+// store after a restart can still be completed normally.
+func (s *TxQueueTestSuite) TestCompletionAfterRestart() {
+	store := common.NewMemTxStore()
+
+	before := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, store)
+	before.Start()
+
+	tx := before.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(before.QueueTransaction(tx))
+	before.Stop()
+
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true))
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(nil, errTxAssumedSent)
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil)
+
+	after := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, store)
+	after.SetGasOracle(s.gasOracleMock)
+	after.Start()
+	defer after.Stop()
+
+	s.True(after.TransactionQueue().Has(tx.ID))
+
+	_, err := after.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+	s.Equal(errTxAssumedSent, err)
+	s.False(after.TransactionQueue().Has(tx.ID))
+}
+
+// drainEvents reads exactly n events off ch, failing the test if they
+// don't arrive within a second.
+func (s *TxQueueTestSuite) drainEvents(ch chan TxEvent, n int) []TxEvent {
+	events := make([]TxEvent, 0, n)
+
+	for i := 0; i < n; i++ {
+		select {
+		case evt := <-ch:
+			events = append(events, evt)
+		case <-time.After(time.Second):
+			s.T().Fatalf("timed out waiting for event: got %d/%d events: %+v", i, n, events)
+		}
+	}
+
+	return events
+}
+
+func (s *TxQueueTestSuite) TestEventSequenceComplete() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true))
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(nil, errTxAssumedSent)
+	s.gasOracleMock.EXPECT().SuggestGasPrice(common.GasPolicyStandard).Return(big.NewInt(1000000000), nil)
+
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.SetGasOracle(s.gasOracleMock)
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	events := make(chan TxEvent, 10)
+	sub := txQueueManager.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	_, err := txQueueManager.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+	s.Equal(errTxAssumedSent, err)
+
+	seen := s.drainEvents(events, 2)
+	s.Equal(TxEventEnqueued, seen[0].Type)
+	s.Equal(TxEventFailed, seen[1].Type)
+	s.Equal(errTxAssumedSent, seen[1].Err)
+	s.Equal(tx.ID, seen[1].ID)
+}
+
+func (s *TxQueueTestSuite) TestEventSequenceDiscard() {
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	events := make(chan TxEvent, 10)
+	sub := txQueueManager.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+	s.NoError(txQueueManager.DiscardTransaction(tx.ID))
+
+	seen := s.drainEvents(events, 2)
+	s.Equal(TxEventEnqueued, seen[0].Type)
+	s.Equal(TxEventDiscarded, seen[1].Type)
+}
+
+func (s *TxQueueTestSuite) TestEventSequenceExpired() {
+	txQueueManager := NewTxQueueManager(s.nodeManagerMock, s.accountManagerMock, common.NewMemTxStore())
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	events := make(chan TxEvent, 10)
+	sub := txQueueManager.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	tx := txQueueManager.CreateTransaction(ctx, common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+
+	seen := s.drainEvents(events, 2)
+	s.Equal(TxEventEnqueued, seen[0].Type)
+	s.Equal(TxEventExpired, seen[1].Type)
+	s.Equal(ErrQueuedTxExpired, seen[1].Err)
+
+	s.Equal(ErrQueuedTxExpired, tx.Err)
+	s.False(txQueueManager.TransactionQueue().Has(tx.ID))
+}
@@ -0,0 +1,32 @@
+package params
+
+// Network ids for well-known Ethereum networks we support.
+const (
+	MainNetworkID    = 1
+	RopstenNetworkID = 3
+	RinkebyNetworkID = 4
+)
+
+// NodeConfig stores configuration options for a geth node, as used by
+// the rest of status-go when starting and interacting with it.
+type NodeConfig struct {
+	// DataDir is the file system folder the node should use for any data storage needs.
+	DataDir string
+
+	// NetworkID is the Ethereum network to connect to (1=mainnet, 3=ropsten, 4=rinkeby).
+	NetworkID uint64
+
+	// UpstreamEnable enables connecting to an upstream (light client) RPC provider
+	// instead of running a full/light node locally.
+	UpstreamEnable bool
+}
+
+// NewNodeConfig creates a new NodeConfig with the given data directory and
+// network id, defaulting everything else not relevant to the caller.
+func NewNodeConfig(dataDir string, networkID uint64, upstreamEnable bool) *NodeConfig {
+	return &NodeConfig{
+		DataDir:        dataDir,
+		NetworkID:      networkID,
+		UpstreamEnable: upstreamEnable,
+	}
+}
@@ -0,0 +1,23 @@
+package testing
+
+// TestAccount describes a keystore account used by the test suites, along
+// with the password needed to unlock it.
+type TestAccount struct {
+	Address  string
+	Password string
+}
+
+// TestConfig holds the fixture accounts shared by the geth/* test suites.
+var TestConfig = struct {
+	Account1 TestAccount
+	Account2 TestAccount
+}{
+	Account1: TestAccount{
+		Address:  "0x4b5c9f6cc66b0d3bf0e41dc2b1c2b7e4c1c5d24f",
+		Password: "test-account1-password",
+	},
+	Account2: TestAccount{
+		Address:  "0x3a1c4cf2b3c8f8f1f02f9f0a8d0e7f2e09d1b3e5",
+		Password: "test-account2-password",
+	},
+}